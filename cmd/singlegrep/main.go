@@ -0,0 +1,172 @@
+// Command singlegrep is the SingleGrep CLI: it scans a file or directory
+// for regex matches and writes a report, driven either by flags or (when
+// none are given) interactive prompts.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/HappyKimoto/SingleGrep/pkg/singlegrep"
+)
+
+func check(e error) {
+	if e != nil {
+		panic(e)
+	}
+}
+
+func getUserInput(promptMessage string) string {
+	// get user input
+	fmt.Print(promptMessage)
+	reader := bufio.NewReader(os.Stdin)
+	userInput, err := reader.ReadString('\n')
+	check(err)
+	// remove CR, LF, and double quotes
+	userInput = strings.ReplaceAll(userInput, "\r\n", "")
+	userInput = strings.ReplaceAll(userInput, "\"", "")
+	// return clean user input
+	return userInput
+}
+
+func getConfig(config *singlegrep.Config, fpConfig *string) {
+	*fpConfig = getUserInput("Setting JSON: ")
+	readConfigFile(config, *fpConfig)
+}
+
+// readConfigFile loads a Config from a JSON settings file.
+func readConfigFile(config *singlegrep.Config, fpConfig string) {
+	bufConfig, err := os.ReadFile(fpConfig)
+	check(err)
+	err = json.Unmarshal(bufConfig, config)
+	check(err)
+}
+
+// writeStdinToTempFile drains stdin into a temp file so the engine, which
+// scans file paths, can scan it like any other file. The caller is
+// responsible for removing the returned path.
+func writeStdinToTempFile() string {
+	tmp, err := os.CreateTemp("", "singlegrep-stdin-*")
+	check(err)
+	defer tmp.Close()
+	_, err = io.Copy(tmp, os.Stdin)
+	check(err)
+	return tmp.Name()
+}
+
+// resolveOutputPath turns dirOut into the report file path to create, or ""
+// to mean stdout. In the interactive flow dirOut is a folder, and the report
+// filename is derived from the settings file; in the flag-driven flow dirOut
+// (from -out) already names the report file directly.
+func resolveOutputPath(interactive bool, dirOut, fpConfig string) string {
+	if dirOut == "-" {
+		return ""
+	}
+	if !interactive {
+		return dirOut
+	}
+	fnOut := strings.Split(filepath.Base(fpConfig), ".")[0]
+	if fnOut == "" {
+		fnOut = "singlegrep"
+	}
+	return filepath.Join(dirOut, fnOut+".txt")
+}
+
+func main() {
+	var (
+		flagConfig    = flag.String("config", "", "path to the Config JSON settings file")
+		flagIn        = flag.String("in", "", "input file or directory to scan (\"-\" for stdin)")
+		flagOut       = flag.String("out", "", "output report path (\"-\" for stdout)")
+		flagPattern   = flag.String("pattern", "", "data regular expression pattern (overrides -config)")
+		flagRecursive = flag.Bool("recursive", false, "search the input directory recursively")
+		flagWorkers   = flag.Int("workers", 0, "number of concurrent scan workers (0 = runtime.NumCPU())")
+		flagFormat    = flag.String("format", "", "output report format (overrides -config)")
+	)
+	flag.Parse()
+
+	// title
+	fmt.Fprintln(os.Stderr, "======= Single Grep ========")
+
+	var config singlegrep.Config
+	var fpConfig, dirIn, dirOut string
+	interactive := *flagIn == "" && *flagConfig == ""
+
+	if !interactive {
+		// non-interactive: driven entirely by flags, with -config as a base
+		fpConfig = *flagConfig
+		if fpConfig != "" {
+			readConfigFile(&config, fpConfig)
+		}
+		dirIn = *flagIn
+		dirOut = *flagOut
+		if dirOut == "" {
+			dirOut = "-"
+		}
+		if *flagPattern != "" {
+			config.DataRegExpPattern = *flagPattern
+		}
+		if *flagRecursive {
+			config.SearchFilesRecursively = true
+		}
+		if *flagWorkers != 0 {
+			config.Workers = *flagWorkers
+		}
+		if *flagFormat != "" {
+			config.OutputFormat = *flagFormat
+		}
+	} else {
+		// interactive fallback: no flags were given
+		getConfig(&config, &fpConfig)
+		dirIn = getUserInput("Data Folder: ")
+		dirOut = getUserInput("Output Folder: ")
+	}
+
+	if dirIn == "-" {
+		tmpPath := writeStdinToTempFile()
+		defer os.Remove(tmpPath)
+		dirIn = tmpPath
+	}
+
+	var out io.Writer
+	fpOut := resolveOutputPath(interactive, dirOut, fpConfig)
+	if fpOut == "" {
+		out = os.Stdout
+	} else {
+		fOut, err := os.Create(fpOut)
+		check(err)
+		defer fOut.Close()
+		out = fOut
+	}
+
+	filesScanned := 0
+	config.OnFile = func(path string) {
+		filesScanned++
+		fmt.Fprintf(os.Stderr, "\rScanned %d files...", filesScanned)
+	}
+	config.OnError = func(path string, err error) singlegrep.ErrorAction {
+		fmt.Fprintf(os.Stderr, "\n%s: %v\n", path, err)
+		return singlegrep.Continue
+	}
+
+	engine := &singlegrep.Engine{Config: config}
+	fmt.Fprintln(os.Stderr, "Scanning...")
+	stats, err := engine.Run(context.Background(), dirIn, out)
+	check(err)
+
+	fmt.Fprintf(os.Stderr, "\nFiles Scanned = %d\n", stats.FilesScanned)
+	fmt.Fprintf(os.Stderr, "Match Count = %d\n", stats.MatchCount)
+	if len(stats.Errors) > 0 {
+		fmt.Fprintf(os.Stderr, "Errors = %d\n", len(stats.Errors))
+	}
+	if fpOut != "" {
+		fmt.Fprintf(os.Stderr, "Wrote file: %q\n", fpOut)
+	}
+	fmt.Fprintln(os.Stderr, "Completed")
+}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestResolveOutputPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		interactive bool
+		dirOut      string
+		fpConfig    string
+		want        string
+	}{
+		{"stdout flag", false, "-", "", ""},
+		{"stdout interactive", true, "-", "", ""},
+		{"flag mode uses -out as a file path", false, "/tmp/results.txt", "", "/tmp/results.txt"},
+		{"interactive mode derives a filename in the folder", true, "/tmp/out", "/tmp/settings.json", "/tmp/out/settings.txt"},
+		{"interactive mode with no settings file falls back to singlegrep.txt", true, "/tmp/out", "", "/tmp/out/singlegrep.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveOutputPath(tt.interactive, tt.dirOut, tt.fpConfig)
+			if got != tt.want {
+				t.Errorf("resolveOutputPath(%v, %q, %q) = %q, want %q", tt.interactive, tt.dirOut, tt.fpConfig, got, tt.want)
+			}
+		})
+	}
+}
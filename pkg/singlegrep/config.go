@@ -0,0 +1,74 @@
+// Package singlegrep is the scanning engine behind the SingleGrep CLI: it
+// walks a file tree (or an injected fs.FS), regex-matches file contents
+// concurrently, and streams the results through a pluggable Formatter.
+package singlegrep
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Config controls how an Engine walks files and matches their contents.
+type Config struct {
+	AbsoluteFilePathRegExpPattern string
+	SearchFilesRecursively        bool
+	SortFilesByModTime            bool
+	DataRegExpPattern             string
+	ColumnHeaderSpaceSeparated    string
+	// Workers is the number of concurrent file-scanning goroutines.
+	// Zero or negative defaults to runtime.NumCPU().
+	Workers int
+	// LineMode scans files line-by-line with bufio.Scanner instead of
+	// loading the whole file and matching submatches against it. It
+	// enables InvertMatch, BeforeContext, AfterContext, and
+	// IncludeLineNumbers below.
+	LineMode bool
+	// InvertMatch, in LineMode, keeps lines that do NOT match
+	// DataRegExpPattern instead of ones that do.
+	InvertMatch bool
+	// BeforeContext is the number of lines to include before each
+	// matching line, in LineMode.
+	BeforeContext int
+	// AfterContext is the number of lines to include after each
+	// matching line, in LineMode.
+	AfterContext int
+	// IncludeLineNumbers prefixes each reported line with its file path
+	// and line number ("path:lineNumber:"), in LineMode.
+	IncludeLineNumbers bool
+	// IncludeGlobs, if non-empty, restricts scanning to files whose path
+	// matches at least one of these doublestar-style globs (e.g.
+	// "**/*.go"). Evaluated in addition to AbsoluteFilePathRegExpPattern.
+	IncludeGlobs []string
+	// ExcludeGlobs skips files (and, for recursive walks, prunes whole
+	// directories) whose path matches any of these doublestar-style
+	// globs (e.g. "**/vendor/**", "**/.git/**").
+	ExcludeGlobs []string
+	// OutputFormat selects the report layout, e.g. "tsv" (the default).
+	OutputFormat string
+	// OnFile, if set, is called once a file's scan completes (before its
+	// matches, if any, are written to the report).
+	OnFile func(path string)
+	// OnMatch, if set, is called for every match as it is written to the
+	// report, in report order.
+	OnMatch func(m Match)
+	// OnError, if set, is notified when a file cannot be read or scanned
+	// and decides what the run does next: Skip or Continue past the file,
+	// or Abort the whole run. If nil, a file error aborts the run, same as
+	// returning Abort.
+	OnError func(path string, err error) ErrorAction
+}
+
+func (c Config) String() string {
+	return fmt.Sprintf("Config; FilePattern=%q; Recursively=%t; RegExp=%q; ColumnHeader=%q; Workers=%d; LineMode=%t; InvertMatch=%t;\n",
+		c.AbsoluteFilePathRegExpPattern, c.SearchFilesRecursively, c.DataRegExpPattern,
+		c.ColumnHeaderSpaceSeparated, c.Workers, c.LineMode, c.InvertMatch)
+}
+
+// workerCount returns the configured worker count, defaulting to the number
+// of available CPUs when unset.
+func (c Config) workerCount() int {
+	if c.Workers > 0 {
+		return c.Workers
+	}
+	return runtime.NumCPU()
+}
@@ -0,0 +1,243 @@
+package singlegrep
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// Stats summarizes a completed Run.
+type Stats struct {
+	// FilesScanned counts files that were read and matched to completion;
+	// it excludes files discovered but skipped past via Config.OnError
+	// (see Errors).
+	FilesScanned int
+	MatchCount   int
+	// Errors collects the per-file errors that Config.OnError let the run
+	// continue past (actions Skip and Continue both skip the file; only
+	// Continue is recorded here).
+	Errors []FileError
+}
+
+// FileError pairs a file path with the error encountered scanning it.
+type FileError struct {
+	Path string
+	Err  error
+}
+
+func (fe FileError) Error() string {
+	return fe.Path + ": " + fe.Err.Error()
+}
+
+// ErrorAction tells Run what to do after Config.OnError reports a file
+// error.
+type ErrorAction int
+
+const (
+	// Skip discards the file and its error, continuing the run silently.
+	Skip ErrorAction = iota
+	// Continue discards the file but records its error in Stats.Errors,
+	// continuing the run.
+	Continue
+	// Abort stops the run, returning the file's error.
+	Abort
+)
+
+// Engine scans files according to Config and writes a report.
+type Engine struct {
+	Config Config
+	// FS, if set, is scanned instead of the local disk; root is then
+	// resolved relative to FS. Tests can set this to an fstest.MapFS to
+	// exercise the engine without touching disk.
+	FS fs.FS
+}
+
+// Run scans root (a file or directory) and streams matches to out as a
+// report in Config.OutputFormat. It can be cancelled early via ctx.
+func (e *Engine) Run(ctx context.Context, root string, out io.Writer) (Stats, error) {
+	cfg := e.Config
+
+	fsys, walkRoot, err := e.resolveFS(root)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	re, err := regexp.Compile(cfg.DataRegExpPattern)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	formatter, err := newFormatter(cfg)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	files, err := listFiles(fsys, walkRoot, cfg)
+	if err != nil {
+		return Stats{}, err
+	}
+	if cfg.SortFilesByModTime {
+		if err := sortFilesByModTime(fsys, files); err != nil {
+			return Stats{}, err
+		}
+	}
+
+	bufOut := bufio.NewWriter(out)
+	bufOut.Write(formatter.Header(cfg))
+
+	filesScanned, matchCount, fileErrors, err := e.scanFiles(ctx, fsys, files, re, cfg, formatter, bufOut)
+
+	bufOut.Write(formatter.Footer(cfg))
+	if flushErr := bufOut.Flush(); flushErr != nil && err == nil {
+		err = flushErr
+	}
+
+	return Stats{FilesScanned: filesScanned, MatchCount: matchCount, Errors: fileErrors}, err
+}
+
+// resolveFS picks the filesystem and root-relative walk path to scan: the
+// injected FS as-is, or the local disk rooted at root's parent directory
+// (so a single file root works the same as os.DirFS(root) does for a
+// directory root).
+func (e *Engine) resolveFS(root string) (fs.FS, string, error) {
+	if e.FS != nil {
+		return e.FS, root, nil
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, "", err
+	}
+	if info.IsDir() {
+		return os.DirFS(root), ".", nil
+	}
+	return os.DirFS(filepath.Dir(root)), filepath.Base(root), nil
+}
+
+// listFiles resolves walkRoot to the list of files to scan: itself, if
+// it's a single file, or the contents of the directory (recursively or
+// not, per cfg.SearchFilesRecursively).
+func listFiles(fsys fs.FS, walkRoot string, cfg Config) ([]string, error) {
+	info, err := fs.Stat(fsys, walkRoot)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{walkRoot}, nil
+	}
+	pathRe, err := regexp.Compile(cfg.AbsoluteFilePathRegExpPattern)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SearchFilesRecursively {
+		return getFilesRecursively(fsys, walkRoot, pathRe, cfg)
+	}
+	return getFilesTopOnly(fsys, walkRoot, pathRe, cfg)
+}
+
+func sortFilesByModTime(fsys fs.FS, files []string) error {
+	var statErr error
+	sort.Slice(files, func(i, j int) bool {
+		ti, err := fsModTime(fsys, files[i])
+		if err != nil {
+			statErr = err
+		}
+		tj, err := fsModTime(fsys, files[j])
+		if err != nil {
+			statErr = err
+		}
+		return ti < tj
+	})
+	return statErr
+}
+
+func fsModTime(fsys fs.FS, path string) (int64, error) {
+	info, err := fs.Stat(fsys, path)
+	if err != nil {
+		return 0, err
+	}
+	return info.ModTime().Unix(), nil
+}
+
+// scanFiles runs the walker/worker/collector pipeline: files are handed out
+// to cfg.workerCount() goroutines for matching, and the collector streams
+// matches, in file order, to out via formatter as they complete.
+// scanFiles returns the number of files scanned to completion, the number of
+// matches written, the errors skipped past via Continue, and the first
+// aborting error (if any).
+func (e *Engine) scanFiles(ctx context.Context, fsys fs.FS, files []string, re *regexp.Regexp, cfg Config, formatter Formatter, out *bufio.Writer) (int, int, []FileError, error) {
+	fileCount := len(files)
+	workerCount := cfg.workerCount()
+	jobs := make(chan fileJob, fileCount)
+	results := make(chan fileResult, workerCount)
+
+	for i, path := range files {
+		jobs <- fileJob{index: i, path: path}
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	wg.Add(workerCount)
+	for w := 0; w < workerCount; w++ {
+		go scanWorker(ctx, fsys, jobs, results, re, cfg, &wg)
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]fileResult)
+	nextIndex := 0
+	filesScanned := 0
+	matchCount := 0
+	var fileErrors []FileError
+	var firstErr error
+
+	flushReady := func() {
+		for {
+			result, ok := pending[nextIndex]
+			if !ok {
+				return
+			}
+			if firstErr == nil && result.err != nil {
+				action := Abort
+				if cfg.OnError != nil {
+					action = cfg.OnError(files[nextIndex], result.err)
+				}
+				switch action {
+				case Skip:
+				case Continue:
+					fileErrors = append(fileErrors, FileError{Path: files[nextIndex], Err: result.err})
+				default:
+					firstErr = result.err
+				}
+			} else if firstErr == nil {
+				filesScanned++
+				if cfg.OnFile != nil {
+					cfg.OnFile(files[nextIndex])
+				}
+				for _, m := range result.matches {
+					if cfg.OnMatch != nil {
+						cfg.OnMatch(m)
+					}
+					out.Write(formatter.Format(m, cfg))
+					matchCount++
+				}
+			}
+			delete(pending, nextIndex)
+			nextIndex++
+		}
+	}
+
+	for result := range results {
+		pending[result.index] = result
+		flushReady()
+	}
+
+	return filesScanned, matchCount, fileErrors, firstErr
+}
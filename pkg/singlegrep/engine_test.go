@@ -0,0 +1,257 @@
+package singlegrep
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// unreadableFS wraps an fs.FS and fails every Open of badPath, to exercise
+// OnError without needing a real unreadable file on disk.
+type unreadableFS struct {
+	fs.FS
+	badPath string
+}
+
+func (f unreadableFS) Open(name string) (fs.File, error) {
+	if name == f.badPath {
+		return nil, errors.New("permission denied")
+	}
+	return f.FS.Open(name)
+}
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"a.txt":            {Data: []byte("hello world\nfoo bar\nhello again\n")},
+		"b.txt":            {Data: []byte("no match here\n")},
+		"vendor/dep.txt":   {Data: []byte("hello from vendor\n")},
+		"src/nested/c.txt": {Data: []byte("hello nested\n")},
+	}
+}
+
+func TestRunSubmatchMode(t *testing.T) {
+	cfg := Config{
+		AbsoluteFilePathRegExpPattern: `\.txt$`,
+		SearchFilesRecursively:        true,
+		DataRegExpPattern:             `(hello)`,
+	}
+	engine := &Engine{Config: cfg, FS: testFS()}
+
+	var out bytes.Buffer
+	stats, err := engine.Run(context.Background(), ".", &out)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if stats.MatchCount != 4 {
+		t.Errorf("MatchCount = %d, want 4", stats.MatchCount)
+	}
+	if stats.FilesScanned != 4 {
+		t.Errorf("FilesScanned = %d, want 4", stats.FilesScanned)
+	}
+}
+
+func TestRunExcludeGlobPrunesDirectory(t *testing.T) {
+	cfg := Config{
+		AbsoluteFilePathRegExpPattern: `\.txt$`,
+		SearchFilesRecursively:        true,
+		DataRegExpPattern:             `(hello)`,
+		ExcludeGlobs:                  []string{"**/vendor/**"},
+	}
+	engine := &Engine{Config: cfg, FS: testFS()}
+
+	var out bytes.Buffer
+	stats, err := engine.Run(context.Background(), ".", &out)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if strings.Contains(out.String(), "vendor") {
+		t.Errorf("report should not contain vendor matches, got %q", out.String())
+	}
+	if stats.FilesScanned != 3 {
+		t.Errorf("FilesScanned = %d, want 3 (vendor pruned)", stats.FilesScanned)
+	}
+}
+
+func TestRunLineModeWithContext(t *testing.T) {
+	cfg := Config{
+		AbsoluteFilePathRegExpPattern: `^a\.txt$`,
+		DataRegExpPattern:             `foo`,
+		LineMode:                      true,
+		BeforeContext:                 1,
+		AfterContext:                  1,
+		IncludeLineNumbers:            true,
+	}
+	engine := &Engine{Config: cfg, FS: testFS()}
+
+	var out bytes.Buffer
+	_, err := engine.Run(context.Background(), "a.txt", &out)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"a.txt:1:", "a.txt:2:", "a.txt:3:"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report %q missing %q", got, want)
+		}
+	}
+}
+
+func TestRunJSONLFormatter(t *testing.T) {
+	cfg := Config{
+		AbsoluteFilePathRegExpPattern: `^a\.txt$`,
+		DataRegExpPattern:             `(?P<word>hello)`,
+		OutputFormat:                  "jsonl",
+	}
+	engine := &Engine{Config: cfg, FS: testFS()}
+
+	var out bytes.Buffer
+	stats, err := engine.Run(context.Background(), "a.txt", &out)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if stats.MatchCount != 2 {
+		t.Fatalf("MatchCount = %d, want 2", stats.MatchCount)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d JSONL lines, want 2", len(lines))
+	}
+	if !strings.Contains(lines[0], `"word":"hello"`) {
+		t.Errorf("line %q missing named group", lines[0])
+	}
+}
+
+func TestRunCallbacks(t *testing.T) {
+	cfg := Config{
+		AbsoluteFilePathRegExpPattern: `\.txt$`,
+		SearchFilesRecursively:        true,
+		DataRegExpPattern:             `(hello)`,
+	}
+	var filesSeen, matchesSeen int
+	cfg.OnFile = func(path string) { filesSeen++ }
+	cfg.OnMatch = func(m Match) { matchesSeen++ }
+	engine := &Engine{Config: cfg, FS: testFS()}
+
+	var out bytes.Buffer
+	if _, err := engine.Run(context.Background(), ".", &out); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if filesSeen != 4 {
+		t.Errorf("OnFile called %d times, want 4", filesSeen)
+	}
+	if matchesSeen != 4 {
+		t.Errorf("OnMatch called %d times, want 4", matchesSeen)
+	}
+}
+
+func TestRunCancelledContext(t *testing.T) {
+	cfg := Config{
+		AbsoluteFilePathRegExpPattern: `\.txt$`,
+		SearchFilesRecursively:        true,
+		DataRegExpPattern:             `(hello)`,
+	}
+	engine := &Engine{Config: cfg, FS: testFS()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out bytes.Buffer
+	_, err := engine.Run(ctx, ".", &out)
+	if err == nil {
+		t.Fatal("Run() with a cancelled context should return an error")
+	}
+}
+
+func TestRunOnErrorContinueRecordsFileError(t *testing.T) {
+	cfg := Config{
+		AbsoluteFilePathRegExpPattern: `\.txt$`,
+		SearchFilesRecursively:        true,
+		DataRegExpPattern:             `(hello)`,
+		OnError:                       func(path string, err error) ErrorAction { return Continue },
+	}
+	fsys := unreadableFS{FS: testFS(), badPath: "b.txt"}
+	engine := &Engine{Config: cfg, FS: fsys}
+
+	var out bytes.Buffer
+	stats, err := engine.Run(context.Background(), ".", &out)
+	if err != nil {
+		t.Fatalf("Run() error = %v, want nil (Continue should not abort)", err)
+	}
+	if len(stats.Errors) != 1 || stats.Errors[0].Path != "b.txt" {
+		t.Errorf("Errors = %+v, want one FileError for b.txt", stats.Errors)
+	}
+	if stats.FilesScanned != 3 {
+		t.Errorf("FilesScanned = %d, want 3 (the 4 discovered files minus the one continued past)", stats.FilesScanned)
+	}
+}
+
+func TestRunOnErrorSkipDiscardsError(t *testing.T) {
+	cfg := Config{
+		AbsoluteFilePathRegExpPattern: `\.txt$`,
+		SearchFilesRecursively:        true,
+		DataRegExpPattern:             `(hello)`,
+		OnError:                       func(path string, err error) ErrorAction { return Skip },
+	}
+	fsys := unreadableFS{FS: testFS(), badPath: "b.txt"}
+	engine := &Engine{Config: cfg, FS: fsys}
+
+	var out bytes.Buffer
+	stats, err := engine.Run(context.Background(), ".", &out)
+	if err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+	if len(stats.Errors) != 0 {
+		t.Errorf("Errors = %+v, want none (Skip discards the error)", stats.Errors)
+	}
+}
+
+func TestRunOnErrorAbortStopsRun(t *testing.T) {
+	cfg := Config{
+		AbsoluteFilePathRegExpPattern: `\.txt$`,
+		SearchFilesRecursively:        true,
+		DataRegExpPattern:             `(hello)`,
+		OnError:                       func(path string, err error) ErrorAction { return Abort },
+	}
+	fsys := unreadableFS{FS: testFS(), badPath: "b.txt"}
+	engine := &Engine{Config: cfg, FS: fsys}
+
+	var out bytes.Buffer
+	_, err := engine.Run(context.Background(), ".", &out)
+	if err == nil {
+		t.Fatal("Run() with Abort should return the file error")
+	}
+}
+
+func TestRunBadFilePathPatternReturnsError(t *testing.T) {
+	cfg := Config{
+		AbsoluteFilePathRegExpPattern: `(unclosed`,
+		SearchFilesRecursively:        true,
+		DataRegExpPattern:             `hello`,
+	}
+	engine := &Engine{Config: cfg, FS: testFS()}
+
+	var out bytes.Buffer
+	if _, err := engine.Run(context.Background(), ".", &out); err == nil {
+		t.Fatal("Run() with a malformed AbsoluteFilePathRegExpPattern should return an error, not panic")
+	}
+}
+
+func TestRunNilOnErrorDefaultsToAbort(t *testing.T) {
+	cfg := Config{
+		AbsoluteFilePathRegExpPattern: `\.txt$`,
+		SearchFilesRecursively:        true,
+		DataRegExpPattern:             `(hello)`,
+	}
+	fsys := unreadableFS{FS: testFS(), badPath: "b.txt"}
+	engine := &Engine{Config: cfg, FS: fsys}
+
+	var out bytes.Buffer
+	_, err := engine.Run(context.Background(), ".", &out)
+	if err == nil {
+		t.Fatal("Run() with nil OnError should abort on a file error")
+	}
+}
@@ -0,0 +1,195 @@
+package singlegrep
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Match is one reported result from a scan: either a regex submatch (with
+// named capture groups) or a matched line with its surrounding context,
+// depending on Config.LineMode.
+type Match struct {
+	File       string
+	LineNumber int // 1-based; only set when LineMode is on
+	Line       string
+	Columns    []string          // submatch groups after the full match
+	Groups     map[string]string // named capture groups, keyed by re.SubexpNames()
+}
+
+// Formatter renders a scan's matches into a report. Header and Footer wrap
+// the stream of Format calls, so formatters that need framing (a JSON
+// array's brackets, a CSV header row) can emit it without buffering the
+// whole report in memory.
+type Formatter interface {
+	Header(cfg Config) []byte
+	Format(m Match, cfg Config) []byte
+	Footer(cfg Config) []byte
+}
+
+// formatterRegistry maps OutputFormat names to Formatter constructors.
+// Register additional formats with RegisterFormatter.
+var formatterRegistry = map[string]func() Formatter{
+	"tsv":   func() Formatter { return &tsvFormatter{} },
+	"csv":   func() Formatter { return &csvFormatter{} },
+	"json":  func() Formatter { return &jsonFormatter{} },
+	"jsonl": func() Formatter { return &jsonlFormatter{} },
+}
+
+// RegisterFormatter adds (or replaces) a named output format, so callers
+// embedding SingleGrep can plug in their own without editing this package.
+func RegisterFormatter(name string, newFormatter func() Formatter) {
+	formatterRegistry[name] = newFormatter
+}
+
+// newFormatter resolves cfg.OutputFormat to a Formatter, defaulting to tsv.
+func newFormatter(cfg Config) (Formatter, error) {
+	name := cfg.OutputFormat
+	if name == "" {
+		name = "tsv"
+	}
+	ctor, ok := formatterRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("singlegrep: unknown output format %q", name)
+	}
+	return ctor(), nil
+}
+
+// tsvFormatter is the original tab-separated report layout.
+type tsvFormatter struct{}
+
+func (f *tsvFormatter) Header(cfg Config) []byte {
+	return []byte(strings.ReplaceAll(cfg.ColumnHeaderSpaceSeparated, " ", "\t") + "\n")
+}
+
+func (f *tsvFormatter) Format(m Match, cfg Config) []byte {
+	return []byte(formatMatchText(m, cfg, "\t") + "\n")
+}
+
+func (f *tsvFormatter) Footer(cfg Config) []byte {
+	return nil
+}
+
+// csvFormatter is the same layout as tsv, properly quoted per RFC 4180.
+type csvFormatter struct{}
+
+func (f *csvFormatter) Header(cfg Config) []byte {
+	return csvEncodeRow(strings.Fields(cfg.ColumnHeaderSpaceSeparated))
+}
+
+func (f *csvFormatter) Format(m Match, cfg Config) []byte {
+	return csvEncodeRow(matchFields(m, cfg))
+}
+
+func (f *csvFormatter) Footer(cfg Config) []byte {
+	return nil
+}
+
+func csvEncodeRow(fields []string) []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(fields); err != nil {
+		panic(err)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+// jsonFormatter renders the whole report as a single JSON array.
+type jsonFormatter struct {
+	wroteAny bool
+}
+
+func (f *jsonFormatter) Header(cfg Config) []byte {
+	return []byte("[\n")
+}
+
+func (f *jsonFormatter) Format(m Match, cfg Config) []byte {
+	enc, err := json.Marshal(matchRecord(m, cfg))
+	if err != nil {
+		panic(err)
+	}
+	var prefix string
+	if f.wroteAny {
+		prefix = ",\n"
+	}
+	f.wroteAny = true
+	return append([]byte(prefix), enc...)
+}
+
+func (f *jsonFormatter) Footer(cfg Config) []byte {
+	return []byte("\n]\n")
+}
+
+// jsonlFormatter renders one JSON object per match, newline-delimited, so
+// large reports can be streamed and consumed line-by-line downstream.
+type jsonlFormatter struct{}
+
+func (f *jsonlFormatter) Header(cfg Config) []byte {
+	return nil
+}
+
+func (f *jsonlFormatter) Format(m Match, cfg Config) []byte {
+	enc, err := json.Marshal(matchRecord(m, cfg))
+	if err != nil {
+		panic(err)
+	}
+	return append(enc, '\n')
+}
+
+func (f *jsonlFormatter) Footer(cfg Config) []byte {
+	return nil
+}
+
+// matchRecordJSON is the JSON shape shared by jsonFormatter and
+// jsonlFormatter: source file, line number (line mode only), matched text,
+// and capture groups, named (Groups) or, absent names, positional
+// (Columns).
+type matchRecordJSON struct {
+	File    string            `json:"file"`
+	Line    int               `json:"line,omitempty"`
+	Text    string            `json:"text,omitempty"`
+	Columns []string          `json:"columns,omitempty"`
+	Groups  map[string]string `json:"groups,omitempty"`
+}
+
+func matchRecord(m Match, cfg Config) matchRecordJSON {
+	rec := matchRecordJSON{File: m.File, Groups: m.Groups}
+	if cfg.LineMode {
+		rec.Line = m.LineNumber
+		rec.Text = m.Line
+	} else if len(m.Groups) == 0 {
+		rec.Columns = m.Columns
+	}
+	return rec
+}
+
+// formatMatchText renders a Match as delimiter-joined plain text, used by
+// the tsv formatter (and the file:line prefix shared with csv).
+func formatMatchText(m Match, cfg Config, sep string) string {
+	if cfg.LineMode {
+		if cfg.IncludeLineNumbers {
+			return m.File + ":" + strconv.Itoa(m.LineNumber) + ":" + m.Line
+		}
+		return m.Line
+	}
+	return strings.Join(m.Columns, sep)
+}
+
+// matchFields renders a Match as a slice of fields, used by the csv
+// formatter to produce one row per match.
+func matchFields(m Match, cfg Config) []string {
+	if cfg.LineMode {
+		if cfg.IncludeLineNumbers {
+			return []string{m.File, strconv.Itoa(m.LineNumber), m.Line}
+		}
+		return []string{m.Line}
+	}
+	return m.Columns
+}
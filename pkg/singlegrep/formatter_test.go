@@ -0,0 +1,83 @@
+package singlegrep
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// mapFSWith builds a single-file fstest.MapFS, for formatter tests that only
+// care about one file's contents.
+func mapFSWith(name, contents string) fstest.MapFS {
+	return fstest.MapFS{name: {Data: []byte(contents)}}
+}
+
+func TestRunCSVFormatter(t *testing.T) {
+	cfg := Config{
+		AbsoluteFilePathRegExpPattern: `^a\.txt$`,
+		DataRegExpPattern:             `(\w+), (\w+)`,
+		ColumnHeaderSpaceSeparated:    "First Second",
+		OutputFormat:                  "csv",
+	}
+	engine := &Engine{Config: cfg, FS: mapFSWith("a.txt", "alpha, beta\n")}
+
+	var out bytes.Buffer
+	if _, err := engine.Run(context.Background(), "a.txt", &out); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d CSV lines, want 2 (header + row): %q", len(lines), out.String())
+	}
+	if lines[0] != "First,Second" {
+		t.Errorf("header = %q, want %q", lines[0], "First,Second")
+	}
+	if lines[1] != "alpha,beta" {
+		t.Errorf("row = %q, want %q", lines[1], "alpha,beta")
+	}
+}
+
+func TestRunJSONFormatterUnnamedGroups(t *testing.T) {
+	cfg := Config{
+		AbsoluteFilePathRegExpPattern: `^a\.txt$`,
+		DataRegExpPattern:             `(\w+) (\d+)`,
+		OutputFormat:                  "json",
+	}
+	engine := &Engine{Config: cfg, FS: mapFSWith("a.txt", "hello 42\n")}
+
+	var out bytes.Buffer
+	stats, err := engine.Run(context.Background(), "a.txt", &out)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if stats.MatchCount != 1 {
+		t.Fatalf("MatchCount = %d, want 1", stats.MatchCount)
+	}
+	got := out.String()
+	if !strings.Contains(got, `"columns":["hello","42"]`) {
+		t.Errorf("report %q missing positional columns for an unnamed-group pattern", got)
+	}
+}
+
+func TestRunJSONLFormatterUnnamedGroups(t *testing.T) {
+	cfg := Config{
+		AbsoluteFilePathRegExpPattern: `^a\.txt$`,
+		DataRegExpPattern:             `(\w+) (\d+)`,
+		OutputFormat:                  "jsonl",
+	}
+	engine := &Engine{Config: cfg, FS: mapFSWith("a.txt", "hello 42\n")}
+
+	var out bytes.Buffer
+	if _, err := engine.Run(context.Background(), "a.txt", &out); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	got := strings.TrimSpace(out.String())
+	if !strings.Contains(got, `"columns":["hello","42"]`) {
+		t.Errorf("record %q missing positional columns for an unnamed-group pattern", got)
+	}
+	if strings.Contains(got, `"groups"`) {
+		t.Errorf("record %q should not report groups when the pattern has none named", got)
+	}
+}
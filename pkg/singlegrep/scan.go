@@ -0,0 +1,129 @@
+package singlegrep
+
+import (
+	"bufio"
+	"context"
+	"io/fs"
+	"regexp"
+	"sync"
+)
+
+const firstColumnIndex = 1
+
+// fileJob is a unit of work handed to a scan worker: the file to read and
+// its position in the original file list, so the collector can put results
+// back in order.
+type fileJob struct {
+	index int
+	path  string
+}
+
+// fileResult is what a scan worker reports back for a fileJob: either the
+// matches it found, ready for a Formatter to render in order, or the error
+// that prevented scanning the file.
+type fileResult struct {
+	index   int
+	matches []Match
+	err     error
+}
+
+// scanFileSubmatches reads the whole file and reports one Match per regex
+// submatch, carrying the captured groups after the full match (and any
+// named ones, keyed by re.SubexpNames()).
+func scanFileSubmatches(fsys fs.FS, path string, re *regexp.Regexp) ([]Match, error) {
+	contents, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	names := re.SubexpNames()
+	matchesCurrent := re.FindAllSubmatch(contents, -1)
+	matches := make([]Match, 0, len(matchesCurrent))
+	for _, m := range matchesCurrent {
+		columns := make([]string, 0, len(m)-firstColumnIndex)
+		var groups map[string]string
+		for i := firstColumnIndex; i < len(m); i++ {
+			columns = append(columns, string(m[i]))
+			if i < len(names) && names[i] != "" {
+				if groups == nil {
+					groups = make(map[string]string)
+				}
+				groups[names[i]] = string(m[i])
+			}
+		}
+		matches = append(matches, Match{File: path, Columns: columns, Groups: groups})
+	}
+	return matches, nil
+}
+
+// scanFileLines scans the file line-by-line with bufio.Scanner, keeping
+// lines that match re (or that don't, when cfg.InvertMatch is set) plus
+// cfg.BeforeContext/cfg.AfterContext lines of surrounding context. This
+// avoids pulling the whole file into memory, unlike scanFileSubmatches.
+func scanFileLines(fsys fs.FS, path string, re *regexp.Regexp, cfg Config) ([]Match, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var allLines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		allLines = append(allLines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	included := make([]bool, len(allLines))
+	for i, line := range allLines {
+		isMatch := re.MatchString(line)
+		if cfg.InvertMatch {
+			isMatch = !isMatch
+		}
+		if !isMatch {
+			continue
+		}
+		start := i - cfg.BeforeContext
+		if start < 0 {
+			start = 0
+		}
+		end := i + cfg.AfterContext
+		if end >= len(allLines) {
+			end = len(allLines) - 1
+		}
+		for j := start; j <= end; j++ {
+			included[j] = true
+		}
+	}
+
+	var matches []Match
+	for i, line := range allLines {
+		if !included[i] {
+			continue
+		}
+		matches = append(matches, Match{File: path, LineNumber: i + 1, Line: line})
+	}
+	return matches, nil
+}
+
+// scanWorker reads files off jobs, scans them for matches according to cfg,
+// and reports the resulting matches (or error) on results. It keeps running
+// until jobs is closed.
+func scanWorker(ctx context.Context, fsys fs.FS, jobs <-chan fileJob, results chan<- fileResult, re *regexp.Regexp, cfg Config, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for job := range jobs {
+		if err := ctx.Err(); err != nil {
+			results <- fileResult{index: job.index, err: err}
+			continue
+		}
+		var matches []Match
+		var err error
+		if cfg.LineMode {
+			matches, err = scanFileLines(fsys, job.path, re, cfg)
+		} else {
+			matches, err = scanFileSubmatches(fsys, job.path, re)
+		}
+		results <- fileResult{index: job.index, matches: matches, err: err}
+	}
+}
@@ -0,0 +1,123 @@
+package singlegrep
+
+import (
+	"io/fs"
+	"regexp"
+	"strings"
+)
+
+// globToRegexp translates a doublestar-style glob into a regular
+// expression: "**" matches any number of path segments (including none),
+// a lone "*" matches within a single segment, and "?" matches one
+// non-separator character. This is a small vendored matcher rather than
+// a dependency on an external doublestar package.
+func globToRegexp(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			sb.WriteString(".*")
+			i += 2
+			if i < len(pattern) && pattern[i] == '/' {
+				i++
+			}
+		case c == '*':
+			sb.WriteString("[^/]*")
+			i++
+		case c == '?':
+			sb.WriteString("[^/]")
+			i++
+		case strings.ContainsRune(`.+()|^$`, rune(c)):
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+			i++
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+func matchesAnyGlob(globs []string, path string) bool {
+	for _, g := range globs {
+		if globToRegexp(g).MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldIncludeFile reports whether path passes cfg's glob filters: it
+// must not match any ExcludeGlobs and, if IncludeGlobs is non-empty, it
+// must match at least one of them.
+func shouldIncludeFile(path string, cfg Config) bool {
+	if len(cfg.ExcludeGlobs) > 0 && matchesAnyGlob(cfg.ExcludeGlobs, path) {
+		return false
+	}
+	if len(cfg.IncludeGlobs) > 0 && !matchesAnyGlob(cfg.IncludeGlobs, path) {
+		return false
+	}
+	return true
+}
+
+// shouldPruneDir reports whether the directory at path should be skipped
+// entirely during a recursive walk, because it (or anything under it)
+// matches an exclude glob, e.g. "**/vendor/**" pruning "vendor".
+func shouldPruneDir(path string, cfg Config) bool {
+	if len(cfg.ExcludeGlobs) == 0 {
+		return false
+	}
+	return matchesAnyGlob(cfg.ExcludeGlobs, path+"/")
+}
+
+func joinFSPath(dir, name string) string {
+	if dir == "." || dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
+
+func getFilesTopOnly(fsys fs.FS, dirIn string, re *regexp.Regexp, cfg Config) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, dirIn)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := joinFSPath(dirIn, entry.Name())
+		if re.MatchString(path) && shouldIncludeFile(path, cfg) {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+func getFilesRecursively(fsys fs.FS, dirIn string, re *regexp.Regexp, cfg Config) ([]string, error) {
+	var files []string
+	err := fs.WalkDir(fsys, dirIn, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// prune whole directory subtrees matched by an exclude glob, so
+		// we never walk into e.g. .git or node_modules
+		if d.IsDir() {
+			if path != dirIn && shouldPruneDir(path, cfg) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		// if file pattern matches and it passes the glob filters, populate the file list
+		if re.MatchString(path) && shouldIncludeFile(path, cfg) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}